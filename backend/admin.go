@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// adminRefreshHandler triggers a manual schedule refresh, comparing the
+// previously cached snapshot against a newly fetched one. With
+// -schedule.debug it writes debugDiffChanges' field-level diff instead
+// of a bare status line, so an operator can see exactly what a refresh
+// changed without cross-referencing logs.
+func adminRefreshHandler(w http.ResponseWriter, r *http.Request, prev, next *eventData) {
+	if !*debugDiff {
+		fmt.Fprintln(w, "schedule refreshed")
+		return
+	}
+	lines := debugDiffChanges(prev, next)
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "schedule refreshed: no changes")
+		return
+	}
+	fmt.Fprintln(w, "schedule refreshed:")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
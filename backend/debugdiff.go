@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// debugDiff makes adminRefreshHandler write a verbose, human-readable
+// field-level diff of every changed session/speaker/video/tag instead
+// of a bare status line. It's off by default: the reflection walk is
+// only worth paying for when an operator is actively debugging a
+// schedule refresh.
+var debugDiff = flag.Bool("schedule.debug", false, "write a human-readable field-level diff in the admin refresh response")
+
+// prettyDiff renders one line per exported field that differs between a
+// and b, which must be pointers to the same struct type (an
+// *eventSession, *eventSpeaker, *eventVideo or *eventTag). label prefixes each
+// line, e.g. `Sessions["keynote"]`.
+func prettyDiff(label string, a, b interface{}) []string {
+	av := reflect.Indirect(reflect.ValueOf(a))
+	bv := reflect.Indirect(reflect.ValueOf(b))
+	if av.Kind() != reflect.Struct || bv.Kind() != reflect.Struct || av.Type() != bv.Type() {
+		return nil
+	}
+	var lines []string
+	t := av.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Name == "Update" { // unexported, or diff metadata itself
+			continue
+		}
+		fa, fb := av.Field(i).Interface(), bv.Field(i).Interface()
+		if reflect.DeepEqual(fa, fb) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s.%s: %s", label, f.Name, diffValue(fa, fb)))
+	}
+	return lines
+}
+
+// diffValue formats how a became b. []string fields render as an
+// add/remove list instead of the full before/after slices, since that's
+// almost always what an operator actually wants to see.
+func diffValue(a, b interface{}) string {
+	if as, ok := a.([]string); ok {
+		if bs, ok := b.([]string); ok {
+			removed := subslice(as, bs...)
+			added := subslice(bs, as...)
+			var parts []string
+			if len(removed) > 0 {
+				parts = append(parts, "-"+strings.Join(removed, ","))
+			}
+			if len(added) > 0 {
+				parts = append(parts, "+"+strings.Join(added, ","))
+			}
+			return strings.Join(parts, " ")
+		}
+	}
+	return fmt.Sprintf("%v -> %v", a, b)
+}
+
+// debugDiffChanges runs diffEventData(a, b) and, for every session,
+// speaker, video and tag it reports changed, renders a prettyDiff
+// against the previous snapshot. Lines are grouped by kind and then ID
+// in a stable order so output doesn't jitter between two otherwise-
+// identical runs.
+func debugDiffChanges(a, b *eventData) []string {
+	dc := diffEventData(a, b)
+
+	var out []string
+	for _, id := range sessionIDs(dc.Sessions) {
+		old, ok := a.Sessions[id]
+		if !ok {
+			old = &eventSession{}
+		}
+		out = append(out, prettyDiff(fmt.Sprintf("Sessions[%q]", id), old, dc.Sessions[id])...)
+	}
+	for _, id := range speakerIDs(dc.Speakers) {
+		old, ok := a.Speakers[id]
+		if !ok {
+			old = &eventSpeaker{}
+		}
+		out = append(out, prettyDiff(fmt.Sprintf("Speakers[%q]", id), old, dc.Speakers[id])...)
+	}
+	for _, id := range videoIDs(dc.Videos) {
+		old, ok := a.Videos[id]
+		if !ok {
+			old = &eventVideo{}
+		}
+		out = append(out, prettyDiff(fmt.Sprintf("Videos[%q]", id), old, dc.Videos[id])...)
+	}
+	for _, id := range tagIDs(dc.Tags) {
+		old, ok := a.Tags[id]
+		if !ok {
+			old = &eventTag{}
+		}
+		out = append(out, prettyDiff(fmt.Sprintf("Tags[%q]", id), old, dc.Tags[id])...)
+	}
+	return out
+}
+
+// sessionIDs returns m's keys in sorted order.
+func sessionIDs(m map[string]*eventSession) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// speakerIDs returns m's keys in sorted order.
+func speakerIDs(m map[string]*eventSpeaker) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// videoIDs returns m's keys in sorted order.
+func videoIDs(m map[string]*eventVideo) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagIDs returns m's keys in sorted order.
+func tagIDs(m map[string]*eventTag) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffValueStringSlice(t *testing.T) {
+	// Same six cases as TestSubslice, read here as "Tags went from in to
+	// (in minus items)" so the add/remove formatting can be checked
+	// against a table we already know is correct.
+	table := []struct{ in, items, out []string }{
+		{[]string{"a", "b", "c"}, []string{"a", "c"}, []string{"b"}},
+		{[]string{"a", "b", "c"}, []string{"a", "c", "b"}, []string{}},
+		{[]string{"a", "b", "c"}, []string{"d"}, []string{"a", "b", "c"}},
+		{[]string{"b", "c"}, []string{"a", "c"}, []string{"b"}},
+		{[]string{"a", "b", "c"}, []string{}, []string{"a", "b", "c"}},
+		{[]string{"abc", "def"}, []string{"ab"}, []string{"abc", "def"}},
+	}
+	for i, test := range table {
+		want := ""
+		removed := subslice(test.in, test.out...)
+		if len(removed) > 0 {
+			want = "-" + joinComma(removed)
+		}
+		if got := diffValue(test.in, test.out); got != want {
+			t.Errorf("%d: diffValue(%v, %v) = %q; want %q", i, test.in, test.out, got, want)
+		}
+	}
+}
+
+func joinComma(s []string) string {
+	out := ""
+	for i, v := range s {
+		if i > 0 {
+			out += ","
+		}
+		out += v
+	}
+	return out
+}
+
+func TestPrettyDiffKeynote(t *testing.T) {
+	a := &eventSession{
+		Title:     "Keynote",
+		StartTime: time.Date(2015, 5, 28, 9, 30, 0, 0, time.UTC),
+		Tags:      []string{"FLAG_KEYNOTE"},
+		Speakers:  []string{"alice", "bob"},
+	}
+	b := &eventSession{
+		Title:     "Keynote",
+		StartTime: time.Date(2015, 5, 28, 9, 30, 0, 0, time.UTC),
+		Tags:      []string{"FLAG_KEYNOTE"},
+		Speakers:  []string{"alice", "bob", "carol"},
+	}
+	lines := prettyDiff(`Sessions["__keynote__"]`, a, b)
+	want := []string{`Sessions["__keynote__"].Speakers: +carol`}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("prettyDiff = %v; want %v", lines, want)
+	}
+}
+
+func TestDebugDiffChangesKeynote(t *testing.T) {
+	a := &eventSession{
+		Title:     "Keynote",
+		StartTime: time.Date(2015, 5, 28, 9, 30, 0, 0, time.UTC),
+		Tags:      []string{"FLAG_KEYNOTE"},
+	}
+	b := &eventSession{
+		Title:     "Keynote",
+		StartTime: time.Date(2015, 5, 28, 9, 30, 0, 0, time.UTC),
+		Tags:      []string{"FLAG_KEYNOTE", "FLAG_LIVESTREAM"},
+	}
+	lines := debugDiffChanges(
+		&eventData{Sessions: map[string]*eventSession{"__keynote__": a}},
+		&eventData{Sessions: map[string]*eventSession{"__keynote__": b}},
+	)
+	want := []string{`Sessions["__keynote__"].Tags: +FLAG_LIVESTREAM`}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("debugDiffChanges = %v; want %v", lines, want)
+	}
+}
+
+// TestDebugDiffChangesSpeakerVideoTag covers the three kinds
+// debugDiffChanges grew alongside diffEventData's Speakers/Videos/Tags
+// diffing: a speaker bio edit, a newly added video and a renamed tag —
+// the exact scenarios an operator debugging a schedule refresh needs to
+// see and couldn't before.
+func TestDebugDiffChangesSpeakerVideoTag(t *testing.T) {
+	a := &eventData{
+		Speakers: map[string]*eventSpeaker{"alice": {Name: "Alice", Bio: "old bio"}},
+		Videos:   map[string]*eventVideo{},
+		Tags:     map[string]*eventTag{"TAG1": {Name: "Tag One"}},
+	}
+	b := &eventData{
+		Speakers: map[string]*eventSpeaker{"alice": {Name: "Alice", Bio: "new bio"}},
+		Videos:   map[string]*eventVideo{"vid1": {Title: "Brand new video"}},
+		Tags:     map[string]*eventTag{"TAG1": {Name: "Tag One Renamed"}},
+	}
+	lines := debugDiffChanges(a, b)
+	want := []string{
+		`Speakers["alice"].Bio: old bio -> new bio`,
+		`Videos["vid1"].Title:  -> Brand new video`,
+		`Tags["TAG1"].Name: Tag One -> Tag One Renamed`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("debugDiffChanges = %v; want %v", lines, want)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import "sync"
+
+// Topics fired on the EventSwitch by diffEventDataNotify.
+const (
+	topicSessionAdded          = "session.added"
+	topicSessionRemoved        = "session.removed"
+	topicSessionUpdated        = "session.updated"
+	topicSessionVideoAvailable = "session.video_available"
+	topicSessionTimeChanged    = "session.time_changed"
+	topicSpeakerUpdated        = "speaker.updated"
+	topicVideoAdded            = "video.added"
+	topicTagUpdated            = "tag.updated"
+)
+
+// EventData is the payload delivered to EventSwitch subscribers: the IDs
+// a topic fired for, plus the old and new values that changed.
+type EventData struct {
+	Topic string
+	IDs   []string
+	Old   interface{}
+	New   interface{}
+}
+
+// EventSwitch is a small pub-sub bus that decouples schedule polling from
+// the subsystems that react to it (push notifications, GCM fanout,
+// search-index reindexing, static-asset cache purges). Callers diff two
+// snapshots, queue the resulting events on an EventCache and Flush it so
+// every subscriber sees one atomic batch per poll cycle.
+type EventSwitch struct {
+	mu   sync.Mutex
+	subs map[string][]func(EventData)
+}
+
+// NewEventSwitch returns a ready-to-use EventSwitch.
+func NewEventSwitch() *EventSwitch {
+	return &EventSwitch{subs: make(map[string][]func(EventData))}
+}
+
+// Subscribe registers cb to be called whenever topic fires.
+func (es *EventSwitch) Subscribe(topic string, cb func(EventData)) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.subs[topic] = append(es.subs[topic], cb)
+}
+
+// Unsubscribe removes all callbacks registered for topic.
+func (es *EventSwitch) Unsubscribe(topic string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	delete(es.subs, topic)
+}
+
+// FireEvent synchronously invokes every callback subscribed to e.Topic.
+func (es *EventSwitch) FireEvent(e EventData) {
+	es.mu.Lock()
+	cbs := append([]func(EventData){}, es.subs[e.Topic]...)
+	es.mu.Unlock()
+	for _, cb := range cbs {
+		cb(e)
+	}
+}
+
+// EventCache batches events queued during a single poll-and-diff cycle
+// and fires them on an EventSwitch all at once when Flush is called, so
+// a subscriber never observes a half-applied diff.
+type EventCache struct {
+	es      *EventSwitch
+	pending []EventData
+}
+
+// NewEventCache returns an EventCache that flushes onto es.
+func NewEventCache(es *EventSwitch) *EventCache {
+	return &EventCache{es: es}
+}
+
+// Queue buffers e until Flush is called.
+func (c *EventCache) Queue(e EventData) {
+	c.pending = append(c.pending, e)
+}
+
+// Flush fires every queued event, in order, and empties the batch.
+func (c *EventCache) Flush() {
+	pending := c.pending
+	c.pending = nil
+	for _, e := range pending {
+		c.es.FireEvent(e)
+	}
+}
+
+// diffEventDataNotify runs diffEventData(a, b) and queues the granular
+// events it implies onto ec: a session.added, session.removed or
+// session.updated per changed session, plus session.video_available or
+// session.time_changed whenever the change was specifically a newly
+// published recording or a rescheduled start time; speaker.updated per
+// changed speaker, video.added per newly published video and
+// tag.updated per changed tag. The caller is responsible for calling
+// ec.Flush() once the whole poll cycle (sessions, speakers, videos,
+// tags) has been queued.
+func diffEventDataNotify(ec *EventCache, a, b *eventData) *eventData {
+	dc := diffEventData(a, b)
+	for id, bs := range dc.Sessions {
+		as, existed := a.Sessions[id]
+		topic := topicSessionUpdated
+		if !existed {
+			topic = topicSessionAdded
+		}
+		ec.Queue(EventData{Topic: topic, IDs: []string{id}, Old: as, New: bs})
+		switch bs.Update {
+		case updateVideo:
+			ec.Queue(EventData{Topic: topicSessionVideoAvailable, IDs: []string{id}, Old: as, New: bs})
+		case updateTimeChanged:
+			ec.Queue(EventData{Topic: topicSessionTimeChanged, IDs: []string{id}, Old: as, New: bs})
+		}
+	}
+	for id, as := range a.Sessions {
+		if _, ok := b.Sessions[id]; !ok {
+			ec.Queue(EventData{Topic: topicSessionRemoved, IDs: []string{id}, Old: as, New: nil})
+		}
+	}
+	for id, bs := range dc.Speakers {
+		ec.Queue(EventData{Topic: topicSpeakerUpdated, IDs: []string{id}, Old: a.Speakers[id], New: bs})
+	}
+	for id, bv := range dc.Videos {
+		if _, existed := a.Videos[id]; existed {
+			continue
+		}
+		ec.Queue(EventData{Topic: topicVideoAdded, IDs: []string{id}, Old: nil, New: bv})
+	}
+	for id, bt := range dc.Tags {
+		ec.Queue(EventData{Topic: topicTagUpdated, IDs: []string{id}, Old: a.Tags[id], New: bt})
+	}
+	return dc
+}
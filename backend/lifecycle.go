@@ -0,0 +1,102 @@
+package main
+
+import "time"
+
+// Update reasons describing a session lifecycle transition, set on
+// eventSession.Update by diffEventData alongside updateDetails and
+// updateVideo.
+const (
+	updateStarted    = "started"
+	updateEnded      = "ended"
+	updateSkipped    = "skipped"
+	updateCancelled  = "cancelled"
+	updateReinstated = "reinstated"
+)
+
+// LifecycleState is the state of an eventSession at a point in time,
+// derived from its StartTime, EndTime, IsLive and YouTube fields.
+type LifecycleState int
+
+const (
+	Scheduled LifecycleState = iota
+	Live
+	Ended
+	RecordingAvailable
+	Cancelled
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case Scheduled:
+		return "Scheduled"
+	case Live:
+		return "Live"
+	case Ended:
+		return "Ended"
+	case RecordingAvailable:
+		return "RecordingAvailable"
+	case Cancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// Lifecycle returns s's state as of now. Cancelled sinks every other
+// state; a session is Scheduled until now reaches StartTime, Live from
+// then until EndTime (or for as long as IsLive stays true, in case the
+// feed hasn't caught up with a late end), Ended once it's over with no
+// recording yet, and RecordingAvailable once YouTube is set and the feed
+// no longer reports it as live.
+func (s *eventSession) Lifecycle(now time.Time) LifecycleState {
+	switch {
+	case s.Cancelled:
+		return Cancelled
+	case now.Before(s.StartTime):
+		return Scheduled
+	case s.IsLive:
+		return Live
+	case s.YouTube != "":
+		return RecordingAvailable
+	case now.After(s.EndTime):
+		return Ended
+	default:
+		return Live
+	}
+}
+
+// sessionUpdateReason computes the Update reason diffEventData should
+// record for b relative to a, or "" if nothing worth reporting changed.
+// It promotes the ad-hoc "did a recording just show up" check into a
+// full lifecycle transition, including detecting a session that was
+// live but skipped its recording (ended with no YouTube ID, analogous to
+// a scrobbler's skip detection), calling out a rescheduled StartTime on
+// its own so a reshuffled agenda slot doesn't get lost in the noise of a
+// generic "details changed" reason, and reporting a cancelled session
+// coming back as a reinstatement rather than the generic "details
+// changed" reason sessionEqual's Cancelled comparison would yield.
+func sessionUpdateReason(a, b *eventSession) string {
+	now := time.Now()
+	prev, cur := a.Lifecycle(now), b.Lifecycle(now)
+
+	switch {
+	case cur == Cancelled && prev != Cancelled:
+		return updateCancelled
+	case prev == Cancelled && cur != Cancelled:
+		return updateReinstated
+	case prev == Live && cur == Ended:
+		return updateSkipped
+	case prev == Scheduled && cur == Ended:
+		return updateEnded
+	case prev == Scheduled && cur == Live:
+		return updateStarted
+	case videoBecameAvailable(a, b):
+		return updateVideo
+	case !a.StartTime.Equal(b.StartTime):
+		return updateTimeChanged
+	case !sessionEqual(a, b):
+		return updateDetails
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-2 * time.Hour)
+	end := now.Add(-time.Hour)
+
+	table := []struct {
+		name    string
+		session *eventSession
+		want    LifecycleState
+	}{
+		{
+			name:    "scheduled",
+			session: &eventSession{StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)},
+			want:    Scheduled,
+		},
+		{
+			name:    "early-end: recording already posted before the scheduled end",
+			session: &eventSession{StartTime: start, EndTime: now.Add(time.Hour), YouTube: "recorded"},
+			want:    RecordingAvailable,
+		},
+		{
+			name:    "no-show: ended, never went live, no recording",
+			session: &eventSession{StartTime: start, EndTime: end},
+			want:    Ended,
+		},
+		{
+			name:    "late-start: past StartTime, still marked live",
+			session: &eventSession{StartTime: start, EndTime: now.Add(time.Hour), IsLive: true},
+			want:    Live,
+		},
+		{
+			name:    "live transitioning to recorded",
+			session: &eventSession{StartTime: start, EndTime: end, YouTube: "recorded"},
+			want:    RecordingAvailable,
+		},
+		{
+			name:    "cancelled sinks every other signal",
+			session: &eventSession{StartTime: start, EndTime: now.Add(time.Hour), IsLive: true, Cancelled: true},
+			want:    Cancelled,
+		},
+	}
+	for _, test := range table {
+		if got := test.session.Lifecycle(now); got != test.want {
+			t.Errorf("%s: Lifecycle() = %v; want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSessionUpdateReasonCancelled(t *testing.T) {
+	a := &eventSession{IsLive: true}
+	b := &eventSession{IsLive: true, Cancelled: true}
+	if got := sessionUpdateReason(a, b); got != updateCancelled {
+		t.Errorf("sessionUpdateReason() = %q; want %q", got, updateCancelled)
+	}
+}
+
+func TestSessionUpdateReasonReinstated(t *testing.T) {
+	// Same Title, StartTime, IsLive etc. on both sides: the only
+	// difference is Cancelled flipping back to false, which must be
+	// reported as its own lifecycle transition rather than the generic
+	// details change sessionEqual alone would yield.
+	a := &eventSession{Title: "Keynote", IsLive: true, Cancelled: true}
+	b := &eventSession{Title: "Keynote", IsLive: true, Cancelled: false}
+	if got := sessionUpdateReason(a, b); got != updateReinstated {
+		t.Errorf("sessionUpdateReason() = %q; want %q", got, updateReinstated)
+	}
+}
+
+func TestSessionUpdateReasonTimeChanged(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	a := &eventSession{StartTime: start, EndTime: start.Add(time.Hour), Title: "Keynote"}
+	b := &eventSession{StartTime: start.Add(30 * time.Minute), EndTime: start.Add(time.Hour), Title: "Keynote"}
+	if got := sessionUpdateReason(a, b); got != updateTimeChanged {
+		t.Errorf("sessionUpdateReason() = %q; want %q", got, updateTimeChanged)
+	}
+
+	// A rescheduled slot takes priority over a generic details change
+	// reported alongside it, so an operator isn't left guessing which
+	// field actually moved.
+	b.Title = "Keynote (updated)"
+	if got := sessionUpdateReason(a, b); got != updateTimeChanged {
+		t.Errorf("sessionUpdateReason() with concurrent details change = %q; want %q", got, updateTimeChanged)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mreis1/ioweb2015/backend/thumb"
+)
+
+// Update reasons set on eventSession.Update by diffEventData.
+const (
+	updateDetails     = "details"
+	updateVideo       = "video"
+	updateTimeChanged = "time_changed"
+)
+
+// eventData is a full snapshot of the I/O schedule as served by the
+// upstream JSON feed: sessions, speakers, videos and the tags used to
+// filter them.
+type eventData struct {
+	Sessions map[string]*eventSession
+	Speakers map[string]*eventSpeaker
+	Videos   map[string]*eventVideo
+	Tags     map[string]*eventTag
+
+	// SessionOrder, SpeakerOrder, VideoOrder and TagOrder hold the IDs of
+	// the above maps in display order, computed by Sort. They're nil
+	// until Sort has been called at least once.
+	SessionOrder []string
+	SpeakerOrder []string
+	VideoOrder   []string
+	TagOrder     []string
+}
+
+// eventSession is a single talk/workshop/etc. in the schedule.
+//
+// Update is set by diffEventData on the copy in the eventData it
+// returns; it is never populated on data read straight from the feed.
+type eventSession struct {
+	Update    string `json:"-"`
+	Title     string
+	StartTime time.Time
+	EndTime   time.Time
+	IsLive    bool
+	YouTube   string
+	Cancelled bool
+	Tags      []string
+	Speakers  []string
+	Filters   map[string]bool
+}
+
+// eventSpeaker is a person appearing in one or more eventSessions.
+type eventSpeaker struct {
+	Update string `json:"-"`
+	Name   string
+	Bio    string
+	Thumb  string
+}
+
+// eventVideo is a published conference video, independent of whether it
+// is attached to a session yet.
+type eventVideo struct {
+	Update    string `json:"-"`
+	Title     string
+	YouTube   string
+	Published time.Time
+}
+
+// eventTag is a filterable tag/category shown in the schedule UI.
+type eventTag struct {
+	Update   string `json:"-"`
+	Name     string
+	Category string
+	Order    int
+}
+
+// diffEventData compares two eventData snapshots, usually the currently
+// cached one and a freshly fetched one, and returns the subset of b that
+// changed relative to a. Returned sessions, speakers, videos and tags
+// carry their Update reason.
+func diffEventData(a, b *eventData) *eventData {
+	dc := &eventData{
+		Sessions: make(map[string]*eventSession),
+		Speakers: make(map[string]*eventSpeaker),
+		Videos:   make(map[string]*eventVideo),
+		Tags:     make(map[string]*eventTag),
+	}
+	for id, bs := range b.Sessions {
+		as, existed := a.Sessions[id]
+		if !existed {
+			cp := *bs
+			cp.Update = updateDetails
+			dc.Sessions[id] = &cp
+			continue
+		}
+
+		update := sessionUpdateReason(as, bs)
+		if update == "" {
+			continue
+		}
+		cp := *bs
+		cp.Update = update
+		dc.Sessions[id] = &cp
+	}
+	for id, bs := range b.Speakers {
+		as, existed := a.Speakers[id]
+		if existed && speakerEqual(as, bs) {
+			continue
+		}
+		cp := *bs
+		cp.Update = updateDetails
+		dc.Speakers[id] = &cp
+	}
+	for id, bv := range b.Videos {
+		av, existed := a.Videos[id]
+		if existed && videoEqual(av, bv) {
+			continue
+		}
+		cp := *bv
+		cp.Update = updateDetails
+		dc.Videos[id] = &cp
+	}
+	for id, bt := range b.Tags {
+		at, existed := a.Tags[id]
+		if existed && tagEqual(at, bt) {
+			continue
+		}
+		cp := *bt
+		cp.Update = updateDetails
+		dc.Tags[id] = &cp
+	}
+	return dc
+}
+
+// sessionEqual reports whether a and b carry the same schedule details,
+// treating a nil slice and an empty one as equal since the upstream feed
+// isn't consistent about which it sends.
+func sessionEqual(a, b *eventSession) bool {
+	return a.Title == b.Title &&
+		a.StartTime.Equal(b.StartTime) &&
+		a.Cancelled == b.Cancelled &&
+		stringSliceEqual(a.Tags, b.Tags) &&
+		stringSliceEqual(a.Speakers, b.Speakers) &&
+		reflect.DeepEqual(a.Filters, b.Filters)
+}
+
+// speakerEqual reports whether a and b carry the same speaker details.
+func speakerEqual(a, b *eventSpeaker) bool {
+	return a.Name == b.Name && a.Bio == b.Bio && a.Thumb == b.Thumb
+}
+
+// videoEqual reports whether a and b carry the same video details.
+func videoEqual(a, b *eventVideo) bool {
+	return a.Title == b.Title && a.YouTube == b.YouTube && a.Published.Equal(b.Published)
+}
+
+// tagEqual reports whether a and b carry the same tag details.
+func tagEqual(a, b *eventTag) bool {
+	return a.Name == b.Name && a.Category == b.Category && a.Order == b.Order
+}
+
+// videoBecameAvailable reports whether b represents a session that has
+// ended, is no longer live, and just got a recording that it didn't
+// already have (or had a different one for).
+func videoBecameAvailable(a, b *eventSession) bool {
+	return b.EndTime.Before(time.Now()) && !b.IsLive && b.YouTube != "" && b.YouTube != a.YouTube
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// subslice returns the elements of a that are not present in items,
+// preserving a's order.
+func subslice(a []string, items ...string) []string {
+	out := []string{}
+	for _, v := range a {
+		found := false
+		for _, it := range items {
+			if v == it {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// unique returns a with duplicate entries removed, preserving the order
+// of first occurrence.
+func unique(a []string) []string {
+	out := []string{}
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// thumbURL rewrites a responsive-image placeholder URL, e.g.
+// ".../__w-200-400-600/img.jpg", into a concrete URL using the smallest
+// available width, e.g. ".../w200/img.jpg". URLs without the "__w-"
+// marker are returned unchanged. See package thumb for the
+// srcset/variant helpers templates use instead of this single-URL form.
+func thumbURL(url string) string {
+	return thumb.URL(url)
+}
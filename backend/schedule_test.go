@@ -2,6 +2,7 @@ package main
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 )
@@ -61,17 +62,46 @@ func TestDiffEventData(t *testing.T) {
 	}
 }
 
-func TestDiffEventDataVideo(t *testing.T) {
+// TestDiffEventDataReinstated guards against a cancelled-then-reinstated
+// session silently vanishing from the diff: with every other field
+// identical, only Cancelled flipping back to false must still produce a
+// changed session so the reinstatement reaches the event bus and the
+// debug diff.
+func TestDiffEventDataReinstated(t *testing.T) {
+	a := &eventSession{Title: "Keynote", Cancelled: true}
+	b := &eventSession{Title: "Keynote", Cancelled: false}
+	dc := diffEventData(
+		&eventData{Sessions: map[string]*eventSession{"__keynote__": a}},
+		&eventData{Sessions: map[string]*eventSession{"__keynote__": b}},
+	)
+	if l := len(dc.Sessions); l != 1 {
+		t.Fatalf("len(dc.Sessions) = %d; want 1", l)
+	}
+	if up := dc.Sessions["__keynote__"].Update; up != updateReinstated {
+		t.Errorf("Update = %q; want %q", up, updateReinstated)
+	}
+}
+
+// sessionDiffRow is one row of sessionDiffTable.
+type sessionDiffRow struct {
+	end1, end2   time.Time
+	live1, live2 bool
+	yt1, yt2     string
+	diff         string
+}
+
+// sessionDiffTable enumerates end/live/YouTube transitions for a single
+// session across its possible live/future/past combinations and the
+// Update reason diffEventData should report for each ("" meaning no
+// change at all). TestDiffEventDataVideo and TestDiffEventDataNotifyTopics
+// both drive this same table so the topics an operator actually gets
+// notified on can never drift from the lifecycle reasons diffEventData
+// itself computes.
+func sessionDiffTable() []sessionDiffRow {
 	date := time.Now().Round(time.Second)
 	past := date.Add(-time.Hour)
 	future := date.Add(time.Hour)
-
-	table := []struct {
-		end1, end2   time.Time
-		live1, live2 bool
-		yt1, yt2     string
-		diff         string
-	}{
+	return []sessionDiffRow{
 		// past sessions
 		{past, past, true, false, "live", "recored", updateVideo},
 		{past, past, true, false, "", "recored", updateVideo},
@@ -83,11 +113,19 @@ func TestDiffEventDataVideo(t *testing.T) {
 		{past, past, false, true, "", "live", ""},
 		{past, past, false, true, "recorded", "live", ""},
 		{past, past, false, true, "recorded", "", ""},
-		{past, past, true, false, "live", "", ""},
+		// went live but ended with no recording: skipped, not a bare no-op.
+		// Before chunk0-2's lifecycle model this exact tuple asserted ""
+		// here; skip detection now correctly reports updateSkipped instead,
+		// and the case below adds fresh coverage of the same transition
+		// when the session never had a YouTube ID at all (not just one
+		// that was cleared), so skip detection isn't just an artifact
+		// of yt1 being non-empty.
+		{past, past, true, false, "live", "", updateSkipped},
+		{past, past, true, false, "", "", updateSkipped},
 		{past, past, true, true, "", "live", ""},
 		{past, past, true, true, "live1", "live2", ""},
 		{past, past, true, true, "live1", "", ""},
-		// future sessions; i = 14
+		// future sessions; i = 15
 		{future, future, true, false, "", "", ""},
 		{future, future, true, false, "live", "", ""},
 		{future, future, true, false, "", "recorded", ""},
@@ -103,7 +141,10 @@ func TestDiffEventDataVideo(t *testing.T) {
 		{future, future, false, false, "live", "", ""},
 		{future, future, false, false, "", "live", ""},
 	}
-	for i, test := range table {
+}
+
+func TestDiffEventDataVideo(t *testing.T) {
+	for i, test := range sessionDiffTable() {
 		a := &eventSession{
 			EndTime: test.end1,
 			IsLive:  test.live1,
@@ -142,6 +183,181 @@ func TestDiffEventDataVideo(t *testing.T) {
 	}
 }
 
+func TestEventSwitchFireEvent(t *testing.T) {
+	es := NewEventSwitch()
+	var got []EventData
+	es.Subscribe(topicSessionUpdated, func(e EventData) { got = append(got, e) })
+	es.Subscribe(topicSessionAdded, func(e EventData) { t.Errorf("unexpected fire on %s", e.Topic) })
+
+	es.FireEvent(EventData{Topic: topicSessionUpdated, IDs: []string{"keynote"}})
+	if len(got) != 1 || got[0].IDs[0] != "keynote" {
+		t.Errorf("got %v; want one event for keynote", got)
+	}
+
+	es.Unsubscribe(topicSessionUpdated)
+	es.FireEvent(EventData{Topic: topicSessionUpdated, IDs: []string{"keynote"}})
+	if len(got) != 1 {
+		t.Errorf("got %v; want no new events after Unsubscribe", got)
+	}
+}
+
+func TestEventCacheFlushBatches(t *testing.T) {
+	es := NewEventSwitch()
+	var fired []string
+	es.Subscribe(topicSessionUpdated, func(e EventData) { fired = append(fired, e.IDs[0]) })
+
+	ec := NewEventCache(es)
+	ec.Queue(EventData{Topic: topicSessionUpdated, IDs: []string{"a"}})
+	ec.Queue(EventData{Topic: topicSessionUpdated, IDs: []string{"b"}})
+	if len(fired) != 0 {
+		t.Fatalf("fired = %v before Flush; want none", fired)
+	}
+	ec.Flush()
+	if !reflect.DeepEqual(fired, []string{"a", "b"}) {
+		t.Errorf("fired = %v; want [a b]", fired)
+	}
+	ec.Flush()
+	if len(fired) != 2 {
+		t.Errorf("fired = %v after second Flush; want no new events", fired)
+	}
+}
+
+// TestDiffEventDataNotifyTopics drives every row of sessionDiffTable (the
+// same 29 live/future/past cases TestDiffEventDataVideo checks) through
+// diffEventDataNotify and asserts the exact topic(s) each row must fire:
+// session.updated whenever diff is non-empty, plus session.video_available
+// exactly when diff is updateVideo. Nothing fires for a "" row.
+func TestDiffEventDataNotifyTopics(t *testing.T) {
+	for i, test := range sessionDiffTable() {
+		a := &eventSession{EndTime: test.end1, IsLive: test.live1, YouTube: test.yt1}
+		b := &eventSession{EndTime: test.end2, IsLive: test.live2, YouTube: test.yt2}
+
+		es := NewEventSwitch()
+		var got []string
+		record := func(e EventData) { got = append(got, e.Topic) }
+		es.Subscribe(topicSessionAdded, record)
+		es.Subscribe(topicSessionRemoved, record)
+		es.Subscribe(topicSessionUpdated, record)
+		es.Subscribe(topicSessionVideoAvailable, record)
+		es.Subscribe(topicSessionTimeChanged, record)
+
+		ec := NewEventCache(es)
+		diffEventDataNotify(ec, &eventData{Sessions: map[string]*eventSession{"id": a}},
+			&eventData{Sessions: map[string]*eventSession{"id": b}})
+		ec.Flush()
+
+		var want []string
+		if test.diff != "" {
+			want = append(want, topicSessionUpdated)
+		}
+		if test.diff == updateVideo {
+			want = append(want, topicSessionVideoAvailable)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: diff(%v, %q, %v, %q) topics = %v; want %v",
+				i, test.live1, test.yt1, test.live2, test.yt2, got, want)
+		}
+	}
+}
+
+// TestDiffEventDataNotifyTimeChanged covers the one reason
+// sessionDiffTable can't produce, since every row there holds StartTime
+// fixed: a rescheduled start time must fire session.time_changed
+// alongside session.updated, not session.video_available.
+func TestDiffEventDataNotifyTimeChanged(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	a := &eventSession{StartTime: start, EndTime: start.Add(time.Hour)}
+	b := &eventSession{StartTime: start.Add(30 * time.Minute), EndTime: start.Add(time.Hour)}
+
+	es := NewEventSwitch()
+	var got []string
+	record := func(e EventData) { got = append(got, e.Topic) }
+	es.Subscribe(topicSessionUpdated, record)
+	es.Subscribe(topicSessionTimeChanged, record)
+	es.Subscribe(topicSessionVideoAvailable, record)
+
+	ec := NewEventCache(es)
+	diffEventDataNotify(ec, &eventData{Sessions: map[string]*eventSession{"id": a}},
+		&eventData{Sessions: map[string]*eventSession{"id": b}})
+	ec.Flush()
+
+	want := []string{topicSessionUpdated, topicSessionTimeChanged}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topics = %v; want %v", got, want)
+	}
+}
+
+// TestDiffEventDataNotifyAddedRemoved covers the two transitions
+// sessionDiffTable can't express, since every row there has the session
+// present on both sides: a brand-new session fires session.added and a
+// session dropped from the feed fires session.removed, with neither
+// touching the other's topic.
+func TestDiffEventDataNotifyAddedRemoved(t *testing.T) {
+	s := &eventSession{Title: "Keynote"}
+
+	es := NewEventSwitch()
+	var got []string
+	record := func(e EventData) { got = append(got, e.Topic) }
+	es.Subscribe(topicSessionAdded, record)
+	es.Subscribe(topicSessionRemoved, record)
+	es.Subscribe(topicSessionUpdated, record)
+
+	ec := NewEventCache(es)
+	diffEventDataNotify(ec, &eventData{Sessions: map[string]*eventSession{}},
+		&eventData{Sessions: map[string]*eventSession{"id": s}})
+	ec.Flush()
+	if want := []string{topicSessionAdded}; !reflect.DeepEqual(got, want) {
+		t.Errorf("added: topics = %v; want %v", got, want)
+	}
+
+	got = nil
+	diffEventDataNotify(ec, &eventData{Sessions: map[string]*eventSession{"id": s}},
+		&eventData{Sessions: map[string]*eventSession{}})
+	ec.Flush()
+	if want := []string{topicSessionRemoved}; !reflect.DeepEqual(got, want) {
+		t.Errorf("removed: topics = %v; want %v", got, want)
+	}
+}
+
+// TestDiffEventDataNotifySpeakerVideoTag checks the three topics that
+// don't depend on session lifecycle at all: speaker.updated fires for
+// any changed or new speaker, video.added fires only for a video ID that
+// wasn't there before (not for a re-titled existing one), and
+// tag.updated fires for any changed or new tag.
+func TestDiffEventDataNotifySpeakerVideoTag(t *testing.T) {
+	a := &eventData{
+		Speakers: map[string]*eventSpeaker{"alice": {Name: "Alice", Bio: "old bio"}},
+		Videos:   map[string]*eventVideo{"vid1": {Title: "Old title"}},
+		Tags:     map[string]*eventTag{"TAG1": {Name: "Tag One"}},
+	}
+	b := &eventData{
+		Speakers: map[string]*eventSpeaker{"alice": {Name: "Alice", Bio: "new bio"}},
+		Videos: map[string]*eventVideo{
+			"vid1": {Title: "New title"},
+			"vid2": {Title: "Brand new video"},
+		},
+		Tags: map[string]*eventTag{"TAG1": {Name: "Tag One Renamed"}},
+	}
+
+	es := NewEventSwitch()
+	var got []string
+	record := func(e EventData) { got = append(got, e.Topic+":"+e.IDs[0]) }
+	es.Subscribe(topicSpeakerUpdated, record)
+	es.Subscribe(topicVideoAdded, record)
+	es.Subscribe(topicTagUpdated, record)
+
+	ec := NewEventCache(es)
+	diffEventDataNotify(ec, a, b)
+	ec.Flush()
+
+	want := []string{"speaker.updated:alice", "video.added:vid2", "tag.updated:TAG1"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topics = %v; want %v", got, want)
+	}
+}
+
 func TestThumbURL(t *testing.T) {
 	table := []struct{ in, out string }{
 		{"http://example.org/image.jpg", "http://example.org/image.jpg"},
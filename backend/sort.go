@@ -0,0 +1,119 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// mountainView is the time zone conference days are bucketed in
+// (Google I/O is held in Mountain View, which observes Pacific time).
+var mountainView = func() *time.Location {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return time.FixedZone("PDT", -7*60*60)
+	}
+	return loc
+}()
+
+// Sort computes deterministic display orderings for d's Sessions,
+// Speakers, Videos and Tags, caching them in SessionOrder, SpeakerOrder,
+// VideoOrder and TagOrder. Call it once after loading or merging a feed;
+// HTTP handlers can then stream the cached order instead of re-sorting
+// on every request. diffEventData matches entries by ID and does not
+// read these orders.
+func (d *eventData) Sort() {
+	d.SessionOrder = sortedSessionIDs(d.Sessions)
+	d.SpeakerOrder = sortedSpeakerIDs(d.Speakers)
+	d.VideoOrder = sortedVideoIDs(d.Videos)
+	d.TagOrder = sortedTagIDs(d.Tags)
+}
+
+func sortedSessionIDs(m map[string]*eventSession) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := m[ids[i]], m[ids[j]]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func sortedSpeakerIDs(m map[string]*eventSpeaker) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := m[ids[i]], m[ids[j]]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func sortedVideoIDs(m map[string]*eventVideo) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := m[ids[i]], m[ids[j]]
+		if !a.Published.Equal(b.Published) {
+			return a.Published.After(b.Published)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func sortedTagIDs(m map[string]*eventTag) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := m[ids[i]], m[ids[j]]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// SortedSessionsByDay buckets Sessions into one slice per conference day,
+// in Mountain View local time, both the days and each day's sessions
+// ordered chronologically. It calls Sort first if it hasn't run yet.
+func (d *eventData) SortedSessionsByDay() [][]*eventSession {
+	if d.SessionOrder == nil {
+		d.Sort()
+	}
+	var days []string
+	byDay := make(map[string][]*eventSession)
+	for _, id := range d.SessionOrder {
+		s := d.Sessions[id]
+		day := s.StartTime.In(mountainView).Format("2006-01-02")
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], s)
+	}
+	out := make([][]*eventSession, len(days))
+	for i, day := range days {
+		out[i] = byDay[day]
+	}
+	return out
+}
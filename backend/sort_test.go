@@ -0,0 +1,100 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventDataSortSessions(t *testing.T) {
+	day1 := time.Date(2015, 5, 28, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	d := &eventData{Sessions: map[string]*eventSession{
+		"c": {Title: "Zebras", StartTime: day2},
+		"a": {Title: "Keynote", StartTime: day1},
+		"b": {Title: "Androids", StartTime: day1},
+	}}
+	d.Sort()
+	want := []string{"b", "a", "c"}
+	if !reflect.DeepEqual(d.SessionOrder, want) {
+		t.Errorf("SessionOrder = %v; want %v", d.SessionOrder, want)
+	}
+
+	// A shuffled map with the same contents sorts to the same order.
+	shuffled := &eventData{Sessions: map[string]*eventSession{
+		"a": {Title: "Keynote", StartTime: day1},
+		"b": {Title: "Androids", StartTime: day1},
+		"c": {Title: "Zebras", StartTime: day2},
+	}}
+	shuffled.Sort()
+	if !reflect.DeepEqual(shuffled.SessionOrder, want) {
+		t.Errorf("shuffled SessionOrder = %v; want %v", shuffled.SessionOrder, want)
+	}
+}
+
+func TestEventDataSortSpeakersVideosTags(t *testing.T) {
+	d := &eventData{
+		Speakers: map[string]*eventSpeaker{
+			"s2": {Name: "Bob"},
+			"s1": {Name: "Alice"},
+		},
+		Videos: map[string]*eventVideo{
+			"v1": {Published: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+			"v2": {Published: time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		Tags: map[string]*eventTag{
+			"t1": {Category: "TYPE", Order: 1},
+			"t2": {Category: "TOPIC", Order: 0},
+			"t3": {Category: "TOPIC", Order: 1},
+		},
+	}
+	d.Sort()
+	if want := []string{"s1", "s2"}; !reflect.DeepEqual(d.SpeakerOrder, want) {
+		t.Errorf("SpeakerOrder = %v; want %v", d.SpeakerOrder, want)
+	}
+	if want := []string{"v2", "v1"}; !reflect.DeepEqual(d.VideoOrder, want) {
+		t.Errorf("VideoOrder = %v; want %v", d.VideoOrder, want)
+	}
+	if want := []string{"t2", "t3", "t1"}; !reflect.DeepEqual(d.TagOrder, want) {
+		t.Errorf("TagOrder = %v; want %v", d.TagOrder, want)
+	}
+}
+
+func TestSortedSessionsByDay(t *testing.T) {
+	day1 := time.Date(2015, 5, 28, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	d := &eventData{Sessions: map[string]*eventSession{
+		"a": {Title: "Keynote", StartTime: day1},
+		"b": {Title: "Androids", StartTime: day1},
+		"c": {Title: "Zebras", StartTime: day2},
+	}}
+	byDay := d.SortedSessionsByDay()
+	if len(byDay) != 2 {
+		t.Fatalf("len(byDay) = %d; want 2", len(byDay))
+	}
+	if len(byDay[0]) != 2 || len(byDay[1]) != 1 {
+		t.Errorf("byDay sizes = %d, %d; want 2, 1", len(byDay[0]), len(byDay[1]))
+	}
+	if byDay[0][0].Title != "Androids" || byDay[0][1].Title != "Keynote" {
+		t.Errorf("byDay[0] = %v; want [Androids Keynote]", byDay[0])
+	}
+}
+
+func TestDiffEventDataOrderIndependent(t *testing.T) {
+	s1 := &eventSession{Title: "Keynote", Tags: []string{"FLAG_KEYNOTE"}}
+	s2 := &eventSession{Title: "Androids"}
+
+	a := &eventData{Sessions: map[string]*eventSession{"s1": s1, "s2": s2}}
+	a.Sort()
+	b := &eventData{Sessions: map[string]*eventSession{
+		"s2": {Title: "Androids"},
+		"s1": {Title: "Keynote", Tags: []string{"FLAG_KEYNOTE"}},
+	}}
+	b.Sort()
+
+	dc := diffEventData(a, b)
+	if l := len(dc.Sessions); l != 0 {
+		t.Errorf("len(dc.Sessions) = %d; want 0 for equal, differently-ordered snapshots", l)
+	}
+}
@@ -0,0 +1,120 @@
+// Package thumb rewrites the responsive-image placeholder URLs served by
+// the schedule feed, e.g. ".../__w-200-400-600-800-1000/img.jpg", into
+// concrete variant URLs and the srcset/sizes descriptors templates need
+// to let the browser pick the right one for its viewport and DPR.
+package thumb
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const marker = "__w-"
+
+// Variant is one concrete width a placeholder URL can be rewritten to.
+type Variant struct {
+	Width int
+	URL   string
+}
+
+// parse splits a placeholder URL into the parts before and after its
+// "__w-<widths>/" segment plus the widths themselves, sorted in
+// ascending order. ok is false for URLs without that segment.
+func parse(url string) (prefix, suffix string, widths []int, ok bool) {
+	i := strings.Index(url, marker)
+	if i < 0 {
+		return "", "", nil, false
+	}
+	rest := url[i+len(marker):]
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		return "", "", nil, false
+	}
+	for _, p := range strings.Split(rest[:slash], "-") {
+		w, err := strconv.Atoi(p)
+		if err != nil {
+			return "", "", nil, false
+		}
+		widths = append(widths, w)
+	}
+	if len(widths) == 0 {
+		return "", "", nil, false
+	}
+	sort.Ints(widths)
+	return url[:i], rest[slash:], widths, true
+}
+
+// URL rewrites url to its smallest available variant. URLs without a
+// "__w-" segment are returned unchanged.
+func URL(url string) string {
+	prefix, suffix, widths, ok := parse(url)
+	if !ok {
+		return url
+	}
+	return prefix + "w" + strconv.Itoa(widths[0]) + suffix
+}
+
+// Variants returns every concrete URL a placeholder can be rewritten to,
+// in ascending width order. It returns nil for URLs without a "__w-"
+// segment.
+func Variants(url string) []Variant {
+	prefix, suffix, widths, ok := parse(url)
+	if !ok {
+		return nil
+	}
+	out := make([]Variant, len(widths))
+	for i, w := range widths {
+		out[i] = Variant{Width: w, URL: prefix + "w" + strconv.Itoa(w) + suffix}
+	}
+	return out
+}
+
+// SrcSet builds an HTML srcset value, e.g. "a/w200/x.jpg 200w, ...". When
+// sizes is non-empty only variants at those widths are included; widths
+// not actually offered by url are ignored. URLs without variants are
+// returned unchanged so callers can use the result as a plain src.
+func SrcSet(url string, sizes []int) string {
+	variants := Variants(url)
+	if len(variants) == 0 {
+		return url
+	}
+	if len(sizes) > 0 {
+		allowed := make(map[int]bool, len(sizes))
+		for _, s := range sizes {
+			allowed[s] = true
+		}
+		var filtered []Variant
+		for _, v := range variants {
+			if allowed[v.Width] {
+				filtered = append(filtered, v)
+			}
+		}
+		if len(filtered) > 0 {
+			variants = filtered
+		}
+	}
+	parts := make([]string, len(variants))
+	for i, v := range variants {
+		parts[i] = v.URL + " " + strconv.Itoa(v.Width) + "w"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Preferred returns the smallest variant at least targetWidth wide,
+// falling back to the largest variant available if none is big enough.
+// URLs without variants are returned unchanged.
+func Preferred(url string, targetWidth int) string {
+	variants := Variants(url)
+	if len(variants) == 0 {
+		return url
+	}
+	best := variants[len(variants)-1]
+	for _, v := range variants {
+		if v.Width >= targetWidth {
+			best = v
+			break
+		}
+	}
+	return best.URL
+}
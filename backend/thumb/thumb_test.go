@@ -0,0 +1,84 @@
+package thumb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	table := []struct{ in, out string }{
+		{"http://example.org/image.jpg", "http://example.org/image.jpg"},
+		{"http://example.org/images/__w/img.jpg", "http://example.org/images/__w/img.jpg"},
+		{"http://example.org/images/__w-400-600/img.jpg", "http://example.org/images/w400/img.jpg"},
+		{"http://example.org/__w-200-400-600-800-1000/img.jpg", "http://example.org/w200/img.jpg"},
+	}
+	for _, test := range table {
+		out := URL(test.in)
+		if out != test.out {
+			t.Errorf("URL(%q) = %q; want %q", test.in, out, test.out)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	table := []struct {
+		in  string
+		out []Variant
+	}{
+		{"http://example.org/image.jpg", nil},
+		{"http://example.org/images/__w/img.jpg", nil},
+		{"http://example.org/__w-200-400-600/img.jpg", []Variant{
+			{200, "http://example.org/w200/img.jpg"},
+			{400, "http://example.org/w400/img.jpg"},
+			{600, "http://example.org/w600/img.jpg"},
+		}},
+	}
+	for _, test := range table {
+		out := Variants(test.in)
+		if !reflect.DeepEqual(out, test.out) {
+			t.Errorf("Variants(%q) = %v; want %v", test.in, out, test.out)
+		}
+	}
+}
+
+func TestSrcSet(t *testing.T) {
+	table := []struct {
+		in    string
+		sizes []int
+		out   string
+	}{
+		{"http://example.org/image.jpg", nil, "http://example.org/image.jpg"},
+		{"http://example.org/__w-200-400-600/img.jpg", nil,
+			"http://example.org/w200/img.jpg 200w, http://example.org/w400/img.jpg 400w, http://example.org/w600/img.jpg 600w"},
+		{"http://example.org/__w-200-400-600/img.jpg", []int{200, 600},
+			"http://example.org/w200/img.jpg 200w, http://example.org/w600/img.jpg 600w"},
+		{"http://example.org/__w-200-400-600/img.jpg", []int{9999},
+			"http://example.org/w200/img.jpg 200w, http://example.org/w400/img.jpg 400w, http://example.org/w600/img.jpg 600w"},
+	}
+	for i, test := range table {
+		out := SrcSet(test.in, test.sizes)
+		if out != test.out {
+			t.Errorf("%d: SrcSet(%q, %v) = %q; want %q", i, test.in, test.sizes, out, test.out)
+		}
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	const url = "http://example.org/__w-200-400-600/img.jpg"
+	table := []struct {
+		in     string
+		target int
+		out    string
+	}{
+		{url, 0, "http://example.org/w200/img.jpg"},
+		{url, 300, "http://example.org/w400/img.jpg"},
+		{url, 9999, "http://example.org/w600/img.jpg"},
+		{"http://example.org/image.jpg", 300, "http://example.org/image.jpg"},
+	}
+	for i, test := range table {
+		out := Preferred(test.in, test.target)
+		if out != test.out {
+			t.Errorf("%d: Preferred(%q, %d) = %q; want %q", i, test.in, test.target, out, test.out)
+		}
+	}
+}